@@ -0,0 +1,45 @@
+package shred
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShredWithContextCancelled(t *testing.T) {
+	data := make([]byte, 1<<20) // large enough to not finish in one block
+	filepath := createTestFile(data, t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := ShredWithContext(ctx, Opts{
+		Path:   filepath,
+		Iters:  1,
+		Delete: false,
+		Exact:  true,
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestShredWithOptsProgress(t *testing.T) {
+	data := make([]byte, 1<<16)
+	filepath := createTestFile(data, t)
+
+	var calls int
+	err := ShredWithOpts(Opts{
+		Path:   filepath,
+		Iters:  1,
+		Delete: false,
+		Exact:  true,
+		Progress: func(written, total int64, pass int) {
+			calls++
+			assert.Equal(t, 1, pass)
+			assert.LessOrEqual(t, written, total)
+		},
+	})
+	assert.NoError(t, err)
+	assert.Greater(t, calls, 0)
+}
@@ -0,0 +1,195 @@
+package shred
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// walkEntry is a path discovered while walking a directory tree, along
+// with whether it was a symlink at walk time.
+type walkEntry struct {
+	path    string
+	symlink bool
+}
+
+// ShredDir shreds every regular file under root, optionally descending
+// into subdirectories when opts.Recursive is set, using opts.Workers
+// goroutines in parallel (defaulting to 1). Symlinks are never
+// followed: the link itself is removed rather than shredded, since
+// overwriting it would clobber whatever it points to. Errors from
+// individual files are aggregated into a *MultiError rather than
+// aborting the rest of the tree.
+func ShredDir(root string, opts Opts) error {
+	files, dirs, err := walkDir(root, opts.Recursive)
+	if err != nil {
+		return err
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	// opts is shared by every worker below, and RandSource (when the
+	// caller provides one) isn't guaranteed safe for concurrent Read
+	// calls the way crypto/rand.Reader is, so serialize access to it
+	// across the worker pool rather than handing each goroutine the raw
+	// reader.
+	if opts.RandSource != nil && workers > 1 {
+		opts.RandSource = &syncReader{r: opts.RandSource}
+	}
+
+	jobs := make(chan walkEntry)
+	go func() {
+		defer close(jobs)
+		for _, entry := range files {
+			jobs <- entry
+		}
+	}()
+
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range jobs {
+				if err := shredEntry(entry, opts); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if opts.Delete {
+		errs = append(errs, removeEmptyDirs(append(dirs, root))...)
+	}
+
+	return newMultiError(errs)
+}
+
+// syncReader wraps an io.Reader with a mutex so it can be shared safely
+// across ShredDir's worker goroutines.
+type syncReader struct {
+	mu sync.Mutex
+	r  io.Reader
+}
+
+func (s *syncReader) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.r.Read(p)
+}
+
+// shredEntry shreds a single file discovered by walkDir. Symlinks are
+// only ever removed, never opened, so the target they point to is left
+// untouched.
+func shredEntry(entry walkEntry, opts Opts) error {
+	if entry.symlink {
+		if !opts.Delete {
+			return nil
+		}
+		fs := opts.FS
+		if fs == nil {
+			fs = OSFS
+		}
+		if err := fs.Remove(entry.path); err != nil {
+			return fmt.Errorf("can't remove symlink %s: %w", entry.path, err)
+		}
+		return nil
+	}
+
+	// Guard against the path's type changing between walk and open
+	// (regular file -> symlink, file -> dir), the way restic's walker
+	// does, rather than risk clobbering the wrong object.
+	info, err := os.Lstat(entry.path)
+	if err != nil {
+		return fmt.Errorf("can't stat %s: %w", entry.path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return &ErrTypeChanged{Path: entry.path}
+	}
+
+	fileOpts := opts
+	fileOpts.Path = entry.path
+	if err := ShredWithOpts(fileOpts); err != nil {
+		return fmt.Errorf("can't shred %s: %w", entry.path, err)
+	}
+	return nil
+}
+
+// walkDir collects every symlink and regular file under root (descending
+// into subdirectories only when recursive is set), along with the
+// directories visited so they can be removed bottom-up afterwards.
+func walkDir(root string, recursive bool) ([]walkEntry, []string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't read directory %s: %w", root, err)
+	}
+
+	var files []walkEntry
+	var dirs []string
+	for _, entry := range entries {
+		p := filepath.Join(root, entry.Name())
+		info, err := entry.Info()
+		if err != nil {
+			return nil, nil, fmt.Errorf("can't stat %s: %w", p, err)
+		}
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			files = append(files, walkEntry{path: p, symlink: true})
+		case info.IsDir():
+			if !recursive {
+				continue
+			}
+			subFiles, subDirs, err := walkDir(p, recursive)
+			if err != nil {
+				return nil, nil, err
+			}
+			files = append(files, subFiles...)
+			dirs = append(dirs, subDirs...)
+			dirs = append(dirs, p)
+		case info.Mode().IsRegular():
+			files = append(files, walkEntry{path: p})
+		}
+	}
+	return files, dirs, nil
+}
+
+// removeEmptyDirs removes dirs bottom-up (deepest first), so a parent
+// is only ever removed once all of its children are already gone. A
+// directory that still has children is left in place rather than
+// erroring: with Recursive false that's an untouched subdirectory we
+// never walked into, and even recursively it could be a file that
+// failed to shred above.
+func removeEmptyDirs(dirs []string) []error {
+	sort.Slice(dirs, func(i, j int) bool {
+		return strings.Count(dirs[i], string(os.PathSeparator)) > strings.Count(dirs[j], string(os.PathSeparator))
+	})
+
+	var errs []error
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("can't read directory %s: %w", dir, err))
+			continue
+		}
+		if len(entries) > 0 {
+			continue
+		}
+		if err := os.Remove(dir); err != nil {
+			errs = append(errs, fmt.Errorf("can't remove directory %s: %w", dir, err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,181 @@
+package shred
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShredDirDeletesRegularFiles(t *testing.T) {
+	createTestFile([]byte{'a'}, t) // Make sure testingDir exists
+	dir, err := os.MkdirTemp(testingDir, "shreddir")
+	if err != nil {
+		t.Fatalf("can't create test directory: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	file1 := path.Join(dir, "file1")
+	file2 := path.Join(dir, "file2")
+	for _, f := range []string{file1, file2} {
+		if err := os.WriteFile(f, []byte("test"), 0644); err != nil {
+			t.Fatalf("can't write test file %s: %s", f, err)
+		}
+	}
+
+	err = ShredDir(dir, Opts{Iters: 1, Delete: true, Workers: 2})
+	assert.NoError(t, err)
+
+	_, err = os.Stat(file1)
+	assert.True(t, os.IsNotExist(err), "Expected file1 to be deleted")
+	_, err = os.Stat(file2)
+	assert.True(t, os.IsNotExist(err), "Expected file2 to be deleted")
+}
+
+func TestShredDirDoesNotFollowSymlinks(t *testing.T) {
+	createTestFile([]byte{'a'}, t) // Make sure testingDir exists
+	dir, err := os.MkdirTemp(testingDir, "shreddir")
+	if err != nil {
+		t.Fatalf("can't create test directory: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	target := path.Join(testingDir, "symlink_target")
+	if err := os.WriteFile(target, []byte("keep me"), 0644); err != nil {
+		t.Fatalf("can't write symlink target: %s", err)
+	}
+	t.Cleanup(func() { os.Remove(target) })
+
+	link := path.Join(dir, "link")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatalf("can't create symlink: %s", err)
+	}
+
+	err = ShredDir(dir, Opts{Iters: 1, Delete: true})
+	assert.NoError(t, err)
+
+	_, err = os.Lstat(link)
+	assert.True(t, os.IsNotExist(err), "Expected symlink to be removed")
+
+	data, err := os.ReadFile(target)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("keep me"), data)
+}
+
+func TestShredDirConcurrentRandSource(t *testing.T) {
+	createTestFile([]byte{'a'}, t) // Make sure testingDir exists
+	dir, err := os.MkdirTemp(testingDir, "shreddir")
+	if err != nil {
+		t.Fatalf("can't create test directory: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	for i := 0; i < 8; i++ {
+		f := path.Join(dir, fmt.Sprintf("file%d", i))
+		if err := os.WriteFile(f, []byte("test"), 0644); err != nil {
+			t.Fatalf("can't write test file %s: %s", f, err)
+		}
+	}
+
+	// A bytes.Reader isn't safe for concurrent Read calls, so this only
+	// passes (under -race) if ShredDir serializes access to RandSource
+	// across its worker goroutines rather than sharing it raw.
+	err = ShredDir(dir, Opts{
+		Iters:      1,
+		Delete:     true,
+		Workers:    8,
+		RandSource: bytes.NewReader(bytes.Repeat([]byte{0x42}, 1<<20)),
+	})
+	assert.NoError(t, err)
+}
+
+func TestShredDirRecursiveDescendsAndRemovesEmptyDirs(t *testing.T) {
+	createTestFile([]byte{'a'}, t) // Make sure testingDir exists
+	dir, err := os.MkdirTemp(testingDir, "shreddir")
+	if err != nil {
+		t.Fatalf("can't create test directory: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	topFile := path.Join(dir, "top")
+	if err := os.WriteFile(topFile, []byte("test"), 0644); err != nil {
+		t.Fatalf("can't write top-level file: %s", err)
+	}
+
+	subdir := path.Join(dir, "subdir")
+	if err := os.Mkdir(subdir, 0755); err != nil {
+		t.Fatalf("can't create subdirectory: %s", err)
+	}
+	nested := path.Join(subdir, "nested")
+	if err := os.WriteFile(nested, []byte("test"), 0644); err != nil {
+		t.Fatalf("can't write nested file: %s", err)
+	}
+
+	err = ShredDir(dir, Opts{Iters: 1, Delete: true, Recursive: true})
+	assert.NoError(t, err)
+
+	_, err = os.Stat(topFile)
+	assert.True(t, os.IsNotExist(err), "Expected top-level file to be deleted")
+	_, err = os.Stat(nested)
+	assert.True(t, os.IsNotExist(err), "Expected nested file to be deleted")
+	_, err = os.Stat(subdir)
+	assert.True(t, os.IsNotExist(err), "Expected now-empty subdirectory to be removed")
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err), "Expected now-empty root to be removed")
+}
+
+func TestShredEntryTypeChanged(t *testing.T) {
+	createTestFile([]byte{'a'}, t) // Make sure testingDir exists
+	dir, err := os.MkdirTemp(testingDir, "shreddir")
+	if err != nil {
+		t.Fatalf("can't create test directory: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	// walkEntry claims this is a regular file, but it's actually a
+	// directory, simulating the path's type having changed between
+	// walk and open.
+	turnedIntoDir := path.Join(dir, "turned-into-dir")
+	if err := os.Mkdir(turnedIntoDir, 0755); err != nil {
+		t.Fatalf("can't create directory: %s", err)
+	}
+
+	err = shredEntry(walkEntry{path: turnedIntoDir}, Opts{Iters: 1, Delete: true})
+	typeChanged, ok := err.(*ErrTypeChanged)
+	if assert.True(t, ok, "expected *ErrTypeChanged, got %T: %v", err, err) {
+		assert.Equal(t, turnedIntoDir, typeChanged.Path)
+	}
+}
+
+func TestShredDirNonRecursiveSkipsSubdirs(t *testing.T) {
+	createTestFile([]byte{'a'}, t) // Make sure testingDir exists
+	dir, err := os.MkdirTemp(testingDir, "shreddir")
+	if err != nil {
+		t.Fatalf("can't create test directory: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	subdir := path.Join(dir, "subdir")
+	if err := os.Mkdir(subdir, 0755); err != nil {
+		t.Fatalf("can't create subdirectory: %s", err)
+	}
+	nested := path.Join(subdir, "nested")
+	if err := os.WriteFile(nested, []byte("test"), 0644); err != nil {
+		t.Fatalf("can't write nested file: %s", err)
+	}
+
+	err = ShredDir(dir, Opts{Iters: 1, Delete: true, Recursive: false})
+	assert.NoError(t, err)
+
+	_, err = os.Stat(nested)
+	assert.NoError(t, err, "Expected nested file to be left untouched")
+
+	_, err = os.Stat(subdir)
+	assert.NoError(t, err, "Expected untouched subdirectory to be left in place")
+
+	_, err = os.Stat(dir)
+	assert.NoError(t, err, "Expected root to be left in place since it still has an untouched subdirectory")
+}
@@ -0,0 +1,41 @@
+package shred
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrTypeChanged is reported by ShredDir, as one of the errors in its
+// *MultiError, when a path's type changed
+// between being walked and being opened (e.g. a regular file turned
+// into a symlink, or into a directory). The path is skipped rather than
+// risking shredding the wrong object.
+type ErrTypeChanged struct {
+	Path string
+}
+
+func (e *ErrTypeChanged) Error() string {
+	return fmt.Sprintf("%s changed type between walk and open, skipping", e.Path)
+}
+
+// MultiError aggregates the errors encountered while shredding a
+// directory tree, so that one bad file doesn't abort the whole walk.
+type MultiError struct {
+	Errors []error
+}
+
+func (m *MultiError) Error() string {
+	msgs := make([]string, len(m.Errors))
+	for i, err := range m.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d error(s) occurred:\n\t%s", len(m.Errors), strings.Join(msgs, "\n\t"))
+}
+
+// newMultiError wraps errs in a *MultiError, or returns nil if errs is empty.
+func newMultiError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return &MultiError{Errors: errs}
+}
@@ -0,0 +1,63 @@
+package shred
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+)
+
+// File is the subset of *os.File that ShredWithOpts needs in order to
+// overwrite and (optionally) remove a file. Any type implementing this
+// interface can be plugged in through Opts.FS, which lets shred operate
+// on top of virtual filesystems such as github.com/spf13/afero.
+type File interface {
+	io.WriterAt
+	// ReaderAt is used by Opts.Verify to re-read each block right after
+	// it's written, to confirm the bytes actually landed on disk.
+	io.ReaderAt
+	io.Closer
+	// Seek is used to determine the size of non-regular files (e.g.
+	// block devices) for which Stat().Size() is meaningless.
+	Seek(offset int64, whence int) (int64, error)
+	Stat() (os.FileInfo, error)
+	Sync() error
+}
+
+// FS abstracts the filesystem operations ShredWithOpts relies on, so
+// callers can swap in their own backend (an afero.Fs adapter, an
+// in-memory fake for tests, etc) instead of hitting the real disk.
+type FS interface {
+	OpenFile(name string, flag int, perm os.FileMode) (File, error)
+	Remove(name string) error
+	Stat(name string) (os.FileInfo, error)
+	// BlockSize returns the block size of the filesystem backing name.
+	BlockSize(name string) (int64, error)
+}
+
+// OSFS is the default FS implementation, backed by the real operating
+// system filesystem. It is used whenever Opts.FS is left unset.
+var OSFS FS = osFS{}
+
+type osFS struct{}
+
+func (osFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
+func (osFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+func (osFS) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// BlockSize determines the block size of a file using statfs(2).
+func (osFS) BlockSize(name string) (int64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(name, &stat); err != nil {
+		return -1, fmt.Errorf("can't get block size of %s: %w", name, err)
+	}
+	return stat.Bsize, nil
+}
@@ -0,0 +1,144 @@
+package shred
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memFS is a minimal in-memory FS backing a single named file, used to
+// exercise Opts.FS error paths (short writes, write errors, Sync
+// failures) that Opts.FS was introduced for but that are impractical to
+// trigger reliably against the real disk.
+type memFS struct {
+	name string
+	file *memFile
+}
+
+func newMemFS(name string, size int64) *memFS {
+	return &memFS{name: name, file: &memFile{data: make([]byte, size)}}
+}
+
+func (m *memFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	if name != m.name {
+		return nil, os.ErrNotExist
+	}
+	return m.file, nil
+}
+
+func (m *memFS) Remove(name string) error {
+	if name != m.name {
+		return os.ErrNotExist
+	}
+	return nil
+}
+
+func (m *memFS) Stat(name string) (os.FileInfo, error) {
+	if name != m.name {
+		return nil, os.ErrNotExist
+	}
+	return m.file.Stat()
+}
+
+func (m *memFS) BlockSize(name string) (int64, error) {
+	return 512, nil
+}
+
+// memFile is an in-memory File backed by a byte slice, with knobs to
+// force a short write, a write error, or a Sync failure on demand.
+type memFile struct {
+	data []byte
+
+	shortWriteBy int
+	writeErr     error
+	syncErr      error
+}
+
+func (f *memFile) WriteAt(p []byte, off int64) (int, error) {
+	if f.writeErr != nil {
+		return 0, f.writeErr
+	}
+	n := len(p)
+	if f.shortWriteBy > 0 && f.shortWriteBy < n {
+		n -= f.shortWriteBy
+	}
+	if off+int64(n) > int64(len(f.data)) {
+		n = int(int64(len(f.data)) - off)
+	}
+	copy(f.data[off:], p[:n])
+	return n, nil
+}
+
+func (f *memFile) ReadAt(p []byte, off int64) (int, error) {
+	return copy(p, f.data[off:]), nil
+}
+
+func (f *memFile) Close() error { return nil }
+
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+	return 0, errors.New("seek not supported on memFile")
+}
+
+func (f *memFile) Stat() (os.FileInfo, error) {
+	return memFileInfo{size: int64(len(f.data))}, nil
+}
+
+func (f *memFile) Sync() error {
+	return f.syncErr
+}
+
+type memFileInfo struct {
+	size int64
+}
+
+func (i memFileInfo) Name() string       { return "memfile" }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) Mode() os.FileMode  { return 0644 }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return false }
+func (i memFileInfo) Sys() interface{}   { return nil }
+
+func TestShredWithOptsFakeFSShortWrite(t *testing.T) {
+	fs := newMemFS("fake", 4096)
+	fs.file.shortWriteBy = 7 // every WriteAt returns fewer bytes than asked
+
+	err := ShredWithOpts(Opts{
+		Path:   "fake",
+		Iters:  1,
+		Delete: false,
+		Exact:  true,
+		FS:     fs,
+	})
+	assert.NoError(t, err, "short writes should be retried, not treated as failures")
+}
+
+func TestShredWithOptsFakeFSWriteError(t *testing.T) {
+	fs := newMemFS("fake", 4096)
+	fs.file.writeErr = errors.New("simulated disk failure")
+
+	err := ShredWithOpts(Opts{
+		Path:   "fake",
+		Iters:  1,
+		Delete: false,
+		Exact:  true,
+		FS:     fs,
+	})
+	assert.Error(t, err)
+}
+
+func TestShredWithOptsFakeFSSyncFailure(t *testing.T) {
+	fs := newMemFS("fake", 4096)
+	fs.file.syncErr = errors.New("simulated fsync failure")
+
+	err := ShredWithOpts(Opts{
+		Path:   "fake",
+		Iters:  1,
+		Delete: false,
+		Exact:  true,
+		FS:     fs,
+	})
+	assert.Error(t, err, "a Sync failure after a successful write should still surface as an error")
+}
@@ -0,0 +1,92 @@
+package shred
+
+import (
+	"fmt"
+	"io"
+)
+
+// Pattern describes the byte sequence a single overwrite pass fills the
+// file with. A zero-value Pattern (nil Bytes) fills the pass with
+// cryptographically random data instead of a fixed sequence.
+type Pattern struct {
+	// Bytes is repeated to fill each block written during the pass.
+	// If empty, the pass is filled with random data.
+	Bytes []byte
+}
+
+// bytePattern returns a Pattern that repeats a single byte.
+func bytePattern(b byte) Pattern {
+	return Pattern{Bytes: []byte{b}}
+}
+
+// RandomPattern is a Pattern that fills each pass with random data, the
+// same behavior ShredWithOpts has always had when Patterns is unset.
+var RandomPattern = Pattern{}
+
+// DoDPatterns is the 7-pass DoD 5220.22-M (ECE) overwrite sequence:
+// zeros, ones, random, 0x96, zeros, ones, random.
+var DoDPatterns = []Pattern{
+	bytePattern(0x00),
+	bytePattern(0xFF),
+	RandomPattern,
+	bytePattern(0x96),
+	bytePattern(0x00),
+	bytePattern(0xFF),
+	RandomPattern,
+}
+
+// GutmannPatterns is Peter Gutmann's 35-pass overwrite sequence: four
+// random passes, the 27 fixed patterns from "Secure Deletion of Data
+// from Magnetic and Solid-State Memory", and four more random passes.
+var GutmannPatterns = []Pattern{
+	RandomPattern,
+	RandomPattern,
+	RandomPattern,
+	RandomPattern,
+	{Bytes: []byte{0x55}},
+	{Bytes: []byte{0xAA}},
+	{Bytes: []byte{0x92, 0x49, 0x24}},
+	{Bytes: []byte{0x49, 0x24, 0x92}},
+	{Bytes: []byte{0x24, 0x92, 0x49}},
+	{Bytes: []byte{0x00}},
+	{Bytes: []byte{0x11}},
+	{Bytes: []byte{0x22}},
+	{Bytes: []byte{0x33}},
+	{Bytes: []byte{0x44}},
+	{Bytes: []byte{0x55}},
+	{Bytes: []byte{0x66}},
+	{Bytes: []byte{0x77}},
+	{Bytes: []byte{0x88}},
+	{Bytes: []byte{0x99}},
+	{Bytes: []byte{0xAA}},
+	{Bytes: []byte{0xBB}},
+	{Bytes: []byte{0xCC}},
+	{Bytes: []byte{0xDD}},
+	{Bytes: []byte{0xEE}},
+	{Bytes: []byte{0xFF}},
+	{Bytes: []byte{0x92, 0x49, 0x24}},
+	{Bytes: []byte{0x49, 0x24, 0x92}},
+	{Bytes: []byte{0x24, 0x92, 0x49}},
+	{Bytes: []byte{0x6D, 0xB6, 0xDB}},
+	{Bytes: []byte{0xB6, 0xDB, 0x6D}},
+	{Bytes: []byte{0xDB, 0x6D, 0xB6}},
+	RandomPattern,
+	RandomPattern,
+	RandomPattern,
+	RandomPattern,
+}
+
+// fillBuf fills buf with pattern's repeating byte sequence, or with
+// random data drawn from random if pattern has no fixed bytes.
+func fillBuf(buf []byte, pattern Pattern, random io.Reader) error {
+	if len(pattern.Bytes) == 0 {
+		if _, err := io.ReadFull(random, buf); err != nil {
+			return fmt.Errorf("can't get random bytes: %w", err)
+		}
+		return nil
+	}
+	for i := range buf {
+		buf[i] = pattern.Bytes[i%len(pattern.Bytes)]
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package shred
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShredWithOptsPatternOnly(t *testing.T) {
+	data := []byte{'t', 'e', 's', 't'}
+	filepath := createTestFile(data, t)
+	err := ShredWithOpts(Opts{
+		Path:     filepath,
+		Iters:    1,
+		Delete:   false,
+		Exact:    true,
+		Patterns: []Pattern{bytePattern(0x55)},
+	})
+	assert.NoError(t, err)
+
+	newData, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		t.Fatalf("can't read file %s", filepath)
+	}
+	assert.Equal(t, bytes.Repeat([]byte{0x55}, len(data)), newData)
+}
+
+func TestShredWithOptsPatternsDefaultsItersToOne(t *testing.T) {
+	data := []byte{'t', 'e', 's', 't'}
+	filepath := createTestFile(data, t)
+	err := ShredWithOpts(Opts{
+		Path:     filepath,
+		Delete:   false,
+		Exact:    true,
+		Patterns: []Pattern{bytePattern(0x55)},
+		// Iters deliberately left unset.
+	})
+	assert.NoError(t, err)
+
+	newData, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		t.Fatalf("can't read file %s", filepath)
+	}
+	assert.Equal(t, bytes.Repeat([]byte{0x55}, len(data)), newData, "expected the pattern cycle to run once even though Iters was left at its zero value")
+}
+
+func TestShredWithOptsDoDPatterns(t *testing.T) {
+	data := []byte{'t', 'e', 's', 't'}
+	filepath := createTestFile(data, t)
+	err := ShredWithOpts(Opts{
+		Path:     filepath,
+		Iters:    1,
+		Delete:   false,
+		Exact:    true,
+		Patterns: DoDPatterns,
+	})
+	assert.NoError(t, err)
+
+	// The last DoD pass is random, so only the shape (length) is
+	// checked here; deterministic passes are covered above.
+	newData, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		t.Fatalf("can't read file %s", filepath)
+	}
+	assert.Equal(t, len(data), len(newData))
+}
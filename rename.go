@@ -0,0 +1,122 @@
+package shred
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+)
+
+const randomNameAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// maxRenameAttempts bounds how many random names are tried for a single
+// rename step before giving up, guarding against a pathological
+// directory where collisions keep happening.
+const maxRenameAttempts = 100
+
+// renameWipeSync renames path through a series of random names, the
+// first as long as the original name and each later one a character
+// shorter, down to a single character, in the same directory, fsyncing the
+// parent directory between renames so every directory-entry change is
+// forced to disk before the next one. This mirrors GNU shred's
+// --remove=wipesync and obscures the original filename in the
+// directory's on-disk entries and journal, not just the file's
+// contents. It returns the file's path after the final rename.
+//
+// This operates on the real filesystem directly (os.Rename, directory
+// fsync), since obscuring directory entries this way only makes sense
+// against a real on-disk journal.
+func renameWipeSync(path string) (string, error) {
+	return renameWipeSyncObserved(path, nil)
+}
+
+// renameWipeSyncObserved is renameWipeSync with an onRename hook invoked
+// with the path's name after each intermediate rename, so tests can
+// observe the shrinking-name sequence as it happens instead of just the
+// end state.
+func renameWipeSyncObserved(path string, onRename func(renamedTo string)) (string, error) {
+	dir := filepath.Dir(path)
+	current := path
+
+	for nameLen := len(filepath.Base(path)); nameLen > 0; nameLen-- {
+		next, err := freeRandomName(dir, nameLen)
+		if err != nil {
+			return "", err
+		}
+
+		if err := os.Rename(current, next); err != nil {
+			return "", fmt.Errorf("can't rename %s to %s: %w", current, next, err)
+		}
+		current = next
+		if onRename != nil {
+			onRename(current)
+		}
+
+		if err := syncDir(dir); err != nil {
+			return "", err
+		}
+	}
+
+	return current, nil
+}
+
+// syncDir opens dir and syncs it, forcing the directory entry change
+// made by a preceding rename to persistent storage.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("can't open directory %s: %w", dir, err)
+	}
+	defer d.Close()
+
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("can't sync directory %s: %w", dir, err)
+	}
+	return nil
+}
+
+// freeRandomName returns a path in dir, of the form a random
+// alphanumeric name of length n, that doesn't already exist. os.Rename
+// silently clobbers an existing destination on POSIX, which would be
+// disastrous with names this short (the final step has only
+// len(randomNameAlphabet) possible 1-character names), so candidates
+// are lstat-checked and retried on collision, the way GNU shred's
+// wipename does.
+func freeRandomName(dir string, n int) (string, error) {
+	return freeRandomNameUsing(dir, n, randomName)
+}
+
+// freeRandomNameUsing is freeRandomName parameterized over the name
+// generator, so tests can force collisions deterministically.
+func freeRandomNameUsing(dir string, n int, gen func(int) (string, error)) (string, error) {
+	for attempt := 0; attempt < maxRenameAttempts; attempt++ {
+		name, err := gen(n)
+		if err != nil {
+			return "", err
+		}
+		candidate := filepath.Join(dir, name)
+
+		if _, err := os.Lstat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate, nil
+		} else if err != nil {
+			return "", fmt.Errorf("can't stat %s: %w", candidate, err)
+		}
+		// Candidate already exists, try another random name.
+	}
+	return "", fmt.Errorf("can't find a free name in %s after %d attempts", dir, maxRenameAttempts)
+}
+
+// randomName returns a random alphanumeric name of length n.
+func randomName(n int) (string, error) {
+	buf := make([]byte, n)
+	for i := range buf {
+		idx, err := rand.Int(rand.Reader, big.NewInt(int64(len(randomNameAlphabet))))
+		if err != nil {
+			return "", fmt.Errorf("can't generate random name: %w", err)
+		}
+		buf[i] = randomNameAlphabet[idx.Int64()]
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,117 @@
+package shred
+
+import (
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShredWithOptsRenameBeforeDelete(t *testing.T) {
+	data := []byte{'t', 'e', 's', 't'}
+	filepath := createTestFile(data, t)
+	dir := path.Dir(filepath)
+
+	entriesBefore, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("can't read directory %s: %s", dir, err)
+	}
+
+	err = ShredWithOpts(Opts{
+		Path:               filepath,
+		Iters:              1,
+		Delete:             true,
+		RenameBeforeDelete: true,
+	})
+	assert.NoError(t, err)
+
+	_, err = os.Stat(filepath)
+	assert.True(t, errors.Is(err, os.ErrNotExist), "Expected file to be deleted under its original name")
+
+	entriesAfter, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("can't read directory %s: %s", dir, err)
+	}
+	assert.Equal(t, len(entriesBefore), len(entriesAfter)+1, "Expected no leftover renamed files")
+}
+
+func TestRenameWipeSyncIntermediateNamesAppear(t *testing.T) {
+	data := []byte{'t', 'e', 's', 't'}
+	filepath := createTestFile(data, t)
+	originalName := path.Base(filepath)
+
+	var observed []string
+	final, err := renameWipeSyncObserved(filepath, func(renamedTo string) {
+		// The renamed path must exist under its new name the moment
+		// it's observed, and the original name must already be gone.
+		_, statErr := os.Stat(renamedTo)
+		assert.NoError(t, statErr, "expected intermediate name %s to exist on disk", renamedTo)
+		_, origErr := os.Stat(filepath)
+		assert.True(t, errors.Is(origErr, os.ErrNotExist), "expected the original name to be gone once %s is observed", renamedTo)
+		observed = append(observed, path.Base(renamedTo))
+	})
+	if err != nil {
+		t.Fatalf("can't rename-wipe-sync %s: %s", filepath, err)
+	}
+	t.Cleanup(func() { os.Remove(final) })
+
+	if assert.Equal(t, len(originalName), len(observed), "expected one rename per character of the original name") {
+		for i, name := range observed {
+			assert.Equal(t, len(originalName)-i, len(name), "expected name %d to be %d character(s) shorter than the original", i, i)
+		}
+	}
+	assert.Equal(t, observed[len(observed)-1], path.Base(final))
+
+	_, err = os.Stat(filepath)
+	assert.True(t, errors.Is(err, os.ErrNotExist), "expected the original name to no longer exist")
+}
+
+func TestFreeRandomNameRetriesOnCollision(t *testing.T) {
+	createTestFile([]byte{'a'}, t) // Make sure testingDir exists
+	dir, err := os.MkdirTemp(testingDir, "wipename")
+	if err != nil {
+		t.Fatalf("can't create test directory: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	taken := path.Join(dir, "x")
+	if err := os.WriteFile(taken, []byte("don't clobber me"), 0644); err != nil {
+		t.Fatalf("can't create colliding file: %s", err)
+	}
+
+	calls := 0
+	gen := func(n int) (string, error) {
+		calls++
+		if calls == 1 {
+			return "x", nil // collides with the pre-existing file
+		}
+		return "y", nil
+	}
+
+	candidate, err := freeRandomNameUsing(dir, 1, gen)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls, "expected a retry after the first name collided")
+	assert.Equal(t, path.Join(dir, "y"), candidate)
+
+	data, err := os.ReadFile(taken)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("don't clobber me"), data, "the pre-existing file must survive the collision untouched")
+}
+
+type fakeFS struct{ FS }
+
+func TestShredWithOptsRenameBeforeDeleteRequiresOSFS(t *testing.T) {
+	data := []byte{'t', 'e', 's', 't'}
+	filepath := createTestFile(data, t)
+
+	err := ShredWithOpts(Opts{
+		Path:               filepath,
+		Iters:              1,
+		Delete:             true,
+		RenameBeforeDelete: true,
+		FS:                 fakeFS{FS: OSFS},
+	})
+	assert.Error(t, err, "expected RenameBeforeDelete combined with a non-default FS to be rejected")
+}
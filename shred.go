@@ -1,12 +1,11 @@
 package shred
 
 import (
-	"encoding/binary"
+	"context"
+	cryptorand "crypto/rand"
 	"fmt"
 	"io"
-	"math/rand"
 	"os"
-	"syscall"
 )
 
 type Opts struct {
@@ -19,6 +18,51 @@ type Opts struct {
 	// Do not round file sizes up to the next full block.
 	// This is the default for non-regular files when using Shred(path).
 	Exact bool
+	// FS is the filesystem implementation used to open, stat and remove
+	// the file. Defaults to OSFS, which operates on the real disk.
+	//
+	// Overriding it lets downstream users shred files inside virtual
+	// filesystems (e.g. an github.com/spf13/afero adapter) and lets us
+	// unit-test edge cases like short writes or ENOSPC deterministically.
+	FS FS
+	// Patterns is the sequence of passes to cycle through (e.g.
+	// DoDPatterns or GutmannPatterns). Each element of Iters repeats the
+	// whole sequence. Defaults to a single random pattern repeated Iters
+	// times, matching the historical random-only behavior.
+	Patterns []Pattern
+	// RandSource is the source of random bytes used for random passes.
+	// Defaults to crypto/rand.Reader. Tests can override it with a
+	// deterministic io.Reader. ShredDir serializes access to it across
+	// its worker goroutines, so it need not be safe for concurrent Read
+	// calls itself.
+	RandSource io.Reader
+	// Verify re-reads each block right after it's written and compares
+	// its hash with the bytes just written, failing loudly on any
+	// mismatch. The finished pass is not re-read as a whole. Catches
+	// silent write failures on failing drives.
+	Verify bool
+	// Recursive makes ShredDir descend into subdirectories. Ignored by
+	// ShredWithOpts.
+	Recursive bool
+	// Workers is the number of files ShredDir shreds in parallel.
+	// Defaults to 1 (sequential). Ignored by ShredWithOpts.
+	Workers int
+	// RenameBeforeDelete, combined with Delete, renames the file
+	// through a series of random, shrinking names in its directory
+	// before unlinking it (mirroring GNU shred's --remove=wipesync), so
+	// the filename itself is overwritten in the directory's on-disk
+	// entries and journal, not just the file's contents.
+	//
+	// This bypasses FS: it renames through os.Rename and fsyncs the
+	// real parent directory fd, so it only makes sense against the
+	// real disk. Setting it together with a non-default FS (e.g. an
+	// afero adapter) is an error.
+	RenameBeforeDelete bool
+	// Progress, if set, is invoked every few blocks with the bytes
+	// written so far in the current pass, the pass's total size, and
+	// the 1-indexed pass number. Useful for a live throughput indicator
+	// on multi-TB block devices.
+	Progress func(written, total int64, pass int)
 }
 
 // Shred overwrites a file to hide its contents, and deletes it.
@@ -41,7 +85,34 @@ func Shred(path string) error {
 //
 // For more info look into Opts struct.
 func ShredWithOpts(opts Opts) error {
-	f, err := os.OpenFile(opts.Path, os.O_WRONLY, 0)
+	return ShredWithContext(context.Background(), opts)
+}
+
+// ShredWithContext is like ShredWithOpts, but can be cancelled through
+// ctx. Cancellation is checked between block writes, so a shred of a
+// large block device can be aborted from a signal handler. The pass in
+// progress stops where it is, so the file is left partly overwritten,
+// but it is still synced and closed before ctx's error is returned.
+func ShredWithContext(ctx context.Context, opts Opts) error {
+	fs := opts.FS
+	if fs == nil {
+		fs = OSFS
+	}
+
+	// renameWipeSync always operates on the real filesystem (it needs a
+	// real directory fd to fsync between renames), so it can't be mixed
+	// with a non-default FS such as an afero adapter.
+	if opts.RenameBeforeDelete && fs != OSFS {
+		return fmt.Errorf("RenameBeforeDelete is only supported with the default OSFS, got %T", fs)
+	}
+
+	// Verify needs to read back what was just written, so open O_RDWR
+	// whenever it's enabled; otherwise stick to write-only like before.
+	openFlag := os.O_WRONLY
+	if opts.Verify {
+		openFlag = os.O_RDWR
+	}
+	f, err := fs.OpenFile(opts.Path, openFlag, 0)
 	if err != nil {
 		return fmt.Errorf("can't open %s: %w", opts.Path, err)
 	}
@@ -62,7 +133,7 @@ func ShredWithOpts(opts Opts) error {
 			return fmt.Errorf("can't seek file %s: %w", opts.Path, err)
 		}
 	}
-	blockSize, err := getBlockSize(opts.Path)
+	blockSize, err := fs.BlockSize(opts.Path)
 	if err != nil {
 		return err
 	}
@@ -73,33 +144,64 @@ func ShredWithOpts(opts Opts) error {
 	}
 
 	// Initialize randomness source
-	random, err := newRand()
-	if err != nil {
-		return fmt.Errorf("can't initialize randomness source: %w", err)
+	random := opts.RandSource
+	if random == nil {
+		random = cryptorand.Reader
+	}
+
+	patterns := opts.Patterns
+	if len(patterns) == 0 {
+		patterns = []Pattern{RandomPattern}
+	}
+
+	// A caller who sets Patterns but forgets Iters almost certainly
+	// wants the pattern cycle to run once, not zero times: Iters == 0
+	// with Patterns unset already means "do nothing" (e.g. Delete-only
+	// callers), but silently no-op'ing a whole pattern cycle would make
+	// this a dangerous data-destruction footgun.
+	iters := opts.Iters
+	if len(opts.Patterns) > 0 && iters == 0 {
+		iters = 1
 	}
 
 	// Start shreding
-	for i := 0; i < opts.Iters; i++ {
-		if err := doIteration(f, fileSize, blockSize, random); err != nil {
-			return err
+	var shredErr error
+	pass := 0
+passes:
+	for i := 0; i < iters; i++ {
+		for _, pattern := range patterns {
+			pass++
+			if err := doIteration(ctx, f, fileSize, blockSize, random, pattern, opts.Verify, opts.Progress, pass); err != nil {
+				shredErr = err
+				break passes
+			}
 		}
 	}
 
-	// Synchronize cached writes to persistent storage
-	if err := f.Sync(); err != nil {
-		return fmt.Errorf("can't sync %s: %w", opts.Path, err)
+	// Synchronize cached writes to persistent storage and close the
+	// file even if a pass above was cut short, so a cancelled shred
+	// never leaves the fd dangling.
+	if err := f.Sync(); err != nil && shredErr == nil {
+		shredErr = fmt.Errorf("can't sync %s: %w", opts.Path, err)
 	}
-
-	err = f.Close()
-	if err != nil {
-		return fmt.Errorf("can't close file %s: %w", opts.Path, err)
+	if err := f.Close(); err != nil && shredErr == nil {
+		shredErr = fmt.Errorf("can't close file %s: %w", opts.Path, err)
+	}
+	if shredErr != nil {
+		return shredErr
 	}
 
 	// Delete file if Delete flag is set.
 	if opts.Delete {
-		err := os.Remove(opts.Path)
-		if err != nil {
-			return fmt.Errorf("can't remove file %s: %w", opts.Path, err)
+		removePath := opts.Path
+		if opts.RenameBeforeDelete {
+			removePath, err = renameWipeSync(opts.Path)
+			if err != nil {
+				return err
+			}
+		}
+		if err := fs.Remove(removePath); err != nil {
+			return fmt.Errorf("can't remove file %s: %w", removePath, err)
 		}
 	}
 
@@ -113,60 +215,49 @@ func min(a, b int64) int64 {
 	return b
 }
 
-// doIteration does the actual overwritting of the file.
-func doIteration(f *os.File, fileSize, blockSize int64, random *rand.Rand) error {
-	// Start at begining of file
-	_, err := f.Seek(0, io.SeekStart)
-	if err != nil {
-		return fmt.Errorf("can't seek file: %w", err)
-	}
+// progressBlockInterval is how often (in blocks written) Opts.Progress
+// is invoked during a pass.
+const progressBlockInterval = 16
 
+// doIteration does the actual overwritting of the file for a single
+// pass, filling it with pattern (or random data drawn from random, if
+// pattern is empty). When verify is set, each block is re-read and
+// hashed right after it's written, streaming alongside the write
+// instead of buffering a hash per block for the whole pass. ctx is
+// checked between block writes, and progress (if non-nil) is invoked
+// every progressBlockInterval blocks.
+func doIteration(ctx context.Context, f File, fileSize, blockSize int64, random io.Reader, pattern Pattern, verify bool, progress func(written, total int64, pass int), pass int) error {
 	// Overwrite file
 	buf := make([]byte, blockSize)
+	var readBuf []byte
+	if verify {
+		readBuf = make([]byte, blockSize)
+	}
 	offset := int64(0)
-	for offset < fileSize {
-		_, err := random.Read(buf)
-		if err != nil {
-			return fmt.Errorf("can't get random bytes: %w", err)
+	for blocks := 0; offset < fileSize; blocks++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fillBuf(buf, pattern, random); err != nil {
+			return err
 		}
-		n, err := f.Write(buf[:min(blockSize, fileSize-offset)])
+		chunk := buf[:min(blockSize, fileSize-offset)]
+		n, err := f.WriteAt(chunk, offset)
 		if err != nil {
 			return fmt.Errorf("can't write to file: %w", err)
 		}
+		if verify {
+			if err := verifyBlock(f, chunk[:n], offset, readBuf[:n]); err != nil {
+				return err
+			}
+		}
 		offset += int64(n)
+		if progress != nil && blocks%progressBlockInterval == 0 {
+			progress(offset, fileSize, pass)
+		}
 	}
-	return nil
-}
-
-// getBlockSize determines the block size of a file.
-func getBlockSize(path string) (int64, error) {
-	var stat syscall.Statfs_t
-	if err := syscall.Statfs(path, &stat); err != nil {
-		return -1, fmt.Errorf("can't get block size of %s: %w", path, err)
-	}
-	return stat.Bsize, nil
-}
-
-// newRand initializes a new randomness source.
-//
-// rand.Seed is now deperecated and this is the recommented new way.
-//
-// https://github.com/golang/go/issues/56319
-func newRand() (*rand.Rand, error) {
-	f, err := os.Open("/dev/urandom")
-	if err != nil {
-		return nil, fmt.Errorf("can't open /dev/urandom: %w", err)
-	}
-
-	buf := make([]byte, 8) // 8bytes to fit int64
-	n, err := f.Read(buf)
-	if err != nil {
-		return nil, fmt.Errorf("can't read from /dev/urandom: %w", err)
-	}
-	if n != 8 {
-		return nil, fmt.Errorf("unexpected number of bytes read (%d bytes)", n)
+	if progress != nil {
+		progress(offset, fileSize, pass)
 	}
-
-	seed := int64(binary.BigEndian.Uint64(buf))
-	return rand.New(rand.NewSource(seed)), nil
+	return nil
 }
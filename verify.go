@@ -0,0 +1,42 @@
+package shred
+
+import (
+	"bytes"
+	"fmt"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// hashBlock returns the BLAKE2b-256 hash of a single written block.
+func hashBlock(block []byte) ([]byte, error) {
+	h, err := blake2b.New256(nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't initialize verification hash: %w", err)
+	}
+	h.Write(block)
+	return h.Sum(nil), nil
+}
+
+// verifyBlock re-reads the block just written at offset and compares its
+// hash against want, catching silent write failures on failing drives.
+// It's called block-by-block as each pass is written, so verification
+// streams alongside the write instead of buffering a hash per block for
+// the whole pass, which would blow up memory on multi-TB devices.
+func verifyBlock(f File, want []byte, offset int64, readBuf []byte) error {
+	if _, err := f.ReadAt(readBuf, offset); err != nil {
+		return fmt.Errorf("can't verify block at offset %d: %w", offset, err)
+	}
+
+	wantHash, err := hashBlock(want)
+	if err != nil {
+		return err
+	}
+	gotHash, err := hashBlock(readBuf)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(wantHash, gotHash) {
+		return fmt.Errorf("verification failed: block at offset %d doesn't match what was written", offset)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package shred
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShredWithOptsRandSource(t *testing.T) {
+	data := []byte{'t', 'e', 's', 't'}
+	filepath := createTestFile(data, t)
+	err := ShredWithOpts(Opts{
+		Path:       filepath,
+		Iters:      1,
+		Delete:     false,
+		Exact:      true,
+		RandSource: bytes.NewReader(bytes.Repeat([]byte{0x7A}, 4096)),
+	})
+	assert.NoError(t, err)
+
+	newData, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		t.Fatalf("can't read file %s", filepath)
+	}
+	assert.Equal(t, bytes.Repeat([]byte{0x7A}, len(data)), newData)
+}
+
+func TestShredWithOptsVerify(t *testing.T) {
+	data := []byte{'t', 'e', 's', 't'}
+	filepath := createTestFile(data, t)
+	err := ShredWithOpts(Opts{
+		Path:   filepath,
+		Iters:  1,
+		Delete: false,
+		Exact:  true,
+		Verify: true,
+	})
+	assert.NoError(t, err)
+}